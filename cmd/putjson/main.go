@@ -0,0 +1,367 @@
+// Command putjson extracts delimited translatable blocks from a tree of
+// input files and writes them out in one of several formats. Flag parsing
+// and logging setup live here; the extraction itself is pkg/putjson.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/m4gshm/putjson/pkg/config"
+	"github.com/m4gshm/putjson/pkg/encode"
+	"github.com/m4gshm/putjson/pkg/encode/gomap"
+	jsonenc "github.com/m4gshm/putjson/pkg/encode/json"
+	"github.com/m4gshm/putjson/pkg/encode/po"
+	"github.com/m4gshm/putjson/pkg/encode/toml"
+	"github.com/m4gshm/putjson/pkg/encode/yaml"
+	"github.com/m4gshm/putjson/pkg/putjson"
+	"github.com/m4gshm/putjson/pkg/sum"
+)
+
+const (
+	name = "putjson"
+
+	defaultFormat = "json"
+)
+
+// formatNames lists the -format flag's accepted values, in usage-message order.
+var formatNames = []string{"json", "yaml", "toml", "po", "gomap"}
+
+// encoderFor resolves a -format name to the Encoder it selects and the
+// file extension its output should use.
+func encoderFor(format string) (encode.Encoder, string, error) {
+	switch format {
+	case "json":
+		return jsonenc.NewEncoder(), ".json", nil
+	case "yaml":
+		return yaml.NewEncoder(), ".yaml", nil
+	case "toml":
+		return toml.NewEncoder(), ".toml", nil
+	case "po":
+		return po.NewEncoder(), ".po", nil
+	case "gomap":
+		return gomap.NewEncoder(), ".go", nil
+	default:
+		return nil, "", fmt.Errorf("unknown format %v, expected one of: %v", format, formatNames)
+	}
+}
+
+var (
+	input       = flag.String("input", "", "input directory; must be set")
+	output      = flag.String("output", "", "output directory; must be set")
+	fileMatcher = flag.String("fileMatcher", `\d+_(?P<language>[A-Za-z]{2})_[A-Za-z]{2}_.+.txt`,
+		"regular expression fo file name matching")
+	startToken   = flag.String("startToken", "{{", "start block symbols")
+	endToken     = flag.String("endToken", "}}", "end block symbols")
+	escapeToken  = flag.String("escapeToken", "", "prefix that escapes a start/end token so it is treated as literal text; empty disables escaping")
+	outDirSuffix = flag.String("outSuffix", "-out", "output subdirectory suffix")
+	verbose      = flag.Bool("v", false, "log verbose")
+	langReplace  = flag.String("langReplace", "zh=ch,sv=se", "language code replacers pairs divided by comma; format: source1=replacer1,source2=replacer2")
+	watch        = flag.Bool("watch", false, "after the initial pass, watch rootInput recursively and regenerate output for changed files")
+	format       = flag.String("format", defaultFormat, "output format: "+strings.Join(formatNames, ", "))
+	force        = flag.Bool("force", false, "reprocess every file, bypassing the "+sum.FileName+" cache")
+	check        = flag.Bool("check", false, "exit non-zero if the on-disk outputs disagree with what would be generated; does not write outputs")
+	configPath   = flag.String("config", "", "YAML or TOML config file supplying flag defaults and per-path rules; "+
+		"auto-discovered as "+config.DefaultBaseName+".yaml or "+config.DefaultBaseName+".toml in -input when unset; "+
+		"flags given on the command line always override it")
+)
+
+// watchDebounce is the coalescing window used to collapse the burst of
+// fsnotify events an editor's atomic save produces into a single run.
+const watchDebounce = 200 * time.Millisecond
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage of "+name+":\n")
+	_, _ = fmt.Fprintf(os.Stderr, "\t"+name+" [flags]\n")
+	_, _ = fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func run() error {
+	log.SetPrefix(name + ": ")
+
+	flag.Usage = usage
+	flag.Parse()
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	cfgFile, err := loadConfigFile(explicit)
+	if err != nil {
+		return err
+	}
+
+	rootInput := pickString(explicit, "input", *input, cfgFile.Input)
+	if len(rootInput) == 0 {
+		log.Println("input dir not defined")
+		flag.Usage()
+		os.Exit(1)
+	}
+	rootOutput := pickString(explicit, "output", *output, cfgFile.Output)
+	if len(rootOutput) == 0 {
+		log.Println("output dir not defined")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	outFormat := pickString(explicit, "format", *format, cfgFile.Format)
+	if len(outFormat) == 0 {
+		outFormat = defaultFormat
+	}
+	enc, outExt, err := encoderFor(outFormat)
+	if err != nil {
+		return err
+	}
+
+	verboseVal := pickBool(explicit, "v", *verbose, cfgFile.Verbose)
+	level := slog.LevelInfo
+	if verboseVal {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	escapeTokenVal := *escapeToken
+	if !explicit["escapeToken"] && cfgFile.EscapeToken != nil {
+		escapeTokenVal = *cfgFile.EscapeToken
+	}
+
+	rules, err := buildRules(cfgFile.Rules)
+	if err != nil {
+		return err
+	}
+
+	cfg := putjson.Config{
+		Input:       rootInput,
+		Output:      rootOutput,
+		FileMatcher: pickString(explicit, "fileMatcher", *fileMatcher, cfgFile.FileMatcher),
+		StartToken:  pickString(explicit, "startToken", *startToken, cfgFile.StartToken),
+		EndToken:    pickString(explicit, "endToken", *endToken, cfgFile.EndToken),
+		EscapeToken: escapeTokenVal,
+		OutSuffix:   pickString(explicit, "outSuffix", *outDirSuffix, cfgFile.OutSuffix),
+		OutExt:      outExt,
+		LangReplace: pickLangReplace(explicit, *langReplace, cfgFile.LangReplace),
+		Logger:      logger,
+		Encoder:     enc,
+		Force:       pickBool(explicit, "force", *force, cfgFile.Force),
+		Check:       pickBool(explicit, "check", *check, cfgFile.Check),
+		Rules:       rules,
+	}
+
+	report, err := putjson.Run(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.Check {
+		if failures := report.CheckFailures(); len(failures) > 0 {
+			return fmt.Errorf("%d output(s) out of date", len(failures))
+		}
+	}
+
+	if pickBool(explicit, "watch", *watch, cfgFile.Watch) {
+		return watchInput(rootInput, cfg, logger)
+	}
+
+	return nil
+}
+
+// loadConfigFile resolves -config, falling back to auto-discovery under
+// -input when -config wasn't given explicitly, and loads it. It always
+// returns a non-nil *config.File so callers don't need to nil-check it.
+func loadConfigFile(explicit map[string]bool) (*config.File, error) {
+	path := *configPath
+	if len(path) == 0 && !explicit["config"] && len(*input) > 0 {
+		path = config.DiscoverPath(*input)
+	}
+	if len(path) == 0 {
+		return &config.File{}, nil
+	}
+	f, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("load config %v: %w", path, err)
+	}
+	return f, nil
+}
+
+// buildRules converts config.Rule entries, which name an output format,
+// into putjson.Rule entries, which carry the resolved Encoder and OutExt.
+func buildRules(rules []config.Rule) ([]putjson.Rule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	result := make([]putjson.Rule, len(rules))
+	for i, r := range rules {
+		rule := putjson.Rule{
+			Match:       r.Match,
+			StartToken:  r.StartToken,
+			EndToken:    r.EndToken,
+			FileMatcher: r.FileMatcher,
+			LangReplace: r.LangReplace,
+		}
+		if len(r.Format) > 0 {
+			enc, outExt, err := encoderFor(r.Format)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i, err)
+			}
+			rule.Encoder = enc
+			rule.OutExt = outExt
+		}
+		result[i] = rule
+	}
+	return result, nil
+}
+
+// pickString returns the config file's value for a flag the user didn't
+// pass explicitly, falling back to the flag's own (possibly default)
+// value otherwise.
+func pickString(explicit map[string]bool, flagName, flagVal, cfgVal string) string {
+	if !explicit[flagName] && len(cfgVal) > 0 {
+		return cfgVal
+	}
+	return flagVal
+}
+
+// pickBool is pickString's counterpart for the boolean flags, which use a
+// *bool in config.File to distinguish "absent" from "explicitly false".
+func pickBool(explicit map[string]bool, flagName string, flagVal bool, cfgVal *bool) bool {
+	if !explicit[flagName] && cfgVal != nil {
+		return *cfgVal
+	}
+	return flagVal
+}
+
+// pickLangReplace prefers the config file's langReplace map over the
+// -langReplace flag's string form when the flag wasn't passed explicitly.
+func pickLangReplace(explicit map[string]bool, flagVal string, cfgVal map[string]string) map[string]string {
+	if !explicit["langReplace"] && len(cfgVal) > 0 {
+		return cfgVal
+	}
+	return parseLangReplace(flagVal)
+}
+
+// watchInput observes rootInput recursively for file changes and re-runs
+// putjson.Run on a debounced batch of events. Run's own content-hash cache
+// makes the repeated calls cheap, and its orphan cleanup removes the
+// outputs of any input that was deleted.
+func watchInput(rootInput string, cfg putjson.Config, logger *slog.Logger) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	addDir := func(dir string) error {
+		return filepath.Walk(dir, func(walkPath string, fileInfo os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			} else if fileInfo.IsDir() {
+				if err := watcher.Add(walkPath); err != nil {
+					return fmt.Errorf("watch dir %v: %w", walkPath, err)
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := addDir(rootInput); err != nil {
+		return err
+	}
+
+	logger.Info("watching for changes", "dir", rootInput)
+
+	var (
+		mu      sync.Mutex
+		timer   *time.Timer
+		running bool
+		pending bool
+	)
+
+	// runOnce executes a single Run, then checks whether another change
+	// arrived while it was in flight; if so it schedules itself again
+	// instead of letting a second Run overlap the first.
+	var runOnce func()
+	runOnce = func() {
+		if _, err := putjson.Run(context.Background(), cfg); err != nil {
+			logger.Error("run", "error", err)
+		}
+		mu.Lock()
+		if pending {
+			pending = false
+			mu.Unlock()
+			runOnce()
+			return
+		}
+		running = false
+		mu.Unlock()
+	}
+
+	rerun := func() {
+		mu.Lock()
+		if running {
+			pending = true
+			mu.Unlock()
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(watchDebounce, func() {
+			mu.Lock()
+			running = true
+			mu.Unlock()
+			runOnce()
+		})
+		mu.Unlock()
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if fileInfo, err := os.Stat(event.Name); err == nil && fileInfo.IsDir() {
+					if err := addDir(event.Name); err != nil {
+						logger.Error("watch new dir", "dir", event.Name, "error", err)
+					}
+					continue
+				}
+			}
+			rerun()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Error("watch error", "error", err)
+		}
+	}
+}
+
+// parseLangReplace parses the -langReplace flag's "source1=replacer1,..."
+// form into a map.
+func parseLangReplace(s string) map[string]string {
+	result := make(map[string]string)
+	replacers := strings.Split(s, ",")
+	for _, r := range replacers {
+		pair := strings.Split(r, "=")
+		if len(pair) >= 2 {
+			result[pair[0]] = pair[1]
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+// Package config loads a putjson config file (YAML or TOML), letting a
+// directory of input files supply defaults for the putjson CLI flags
+// instead of repeating them on every invocation, plus a list of rules
+// applying different settings to different subtrees.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBaseName is the file name auto-discovered in an input root when
+// -config is not given; both a YAML and a TOML extension are tried.
+const DefaultBaseName = ".putjson"
+
+// File is the on-disk schema of a putjson config file: it mirrors the
+// CLI flags of the same name so a config file can supply defaults for
+// any of them, plus Rules for per-subtree overrides. Every field is a
+// pointer or has its zero value treated as "not set", so a config file
+// only needs to mention the flags it wants to override, and a flag given
+// explicitly on the command line always wins over the config file.
+type File struct {
+	Input       string            `yaml:"input"`
+	Output      string            `yaml:"output"`
+	FileMatcher string            `yaml:"fileMatcher"`
+	StartToken  string            `yaml:"startToken"`
+	EndToken    string            `yaml:"endToken"`
+	EscapeToken *string           `yaml:"escapeToken"`
+	OutSuffix   string            `yaml:"outSuffix"`
+	Format      string            `yaml:"format"`
+	LangReplace map[string]string `yaml:"langReplace"`
+	Verbose     *bool             `yaml:"v"`
+	Watch       *bool             `yaml:"watch"`
+	Force       *bool             `yaml:"force"`
+	Check       *bool             `yaml:"check"`
+	Rules       []Rule            `yaml:"rules"`
+}
+
+// Rule is one entry of File.Rules: an override of a subset of File's
+// fields, applied to input paths matching Match.
+type Rule struct {
+	// Match is a glob or regexp matched against an input file's path
+	// relative to the input root; which of the two it is interpreted as
+	// is left to the consumer (see pkg/putjson.Rule.Match).
+	Match       string            `yaml:"match"`
+	StartToken  string            `yaml:"startToken"`
+	EndToken    string            `yaml:"endToken"`
+	FileMatcher string            `yaml:"fileMatcher"`
+	LangReplace map[string]string `yaml:"langReplace"`
+	Format      string            `yaml:"format"`
+}
+
+// DiscoverPath looks for DefaultBaseName+".yaml" then DefaultBaseName+
+// ".toml" under inputDir, returning the first one found, or "" if
+// neither exists.
+func DiscoverPath(inputDir string) string {
+	for _, ext := range []string{".yaml", ".toml"} {
+		candidate := filepath.Join(inputDir, DefaultBaseName+ext)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Load reads and parses a config file at path, choosing YAML or TOML
+// decoding by its extension (.yaml/.yml or .toml).
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %v: %w", path, err)
+	}
+
+	var f File
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("parse %v: %w", path, err)
+		}
+	case ".toml":
+		if err := parseTOML(data, &f); err != nil {
+			return nil, fmt.Errorf("parse %v: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("%v: unsupported config extension %v, expected .yaml or .toml", path, ext)
+	}
+	return &f, nil
+}
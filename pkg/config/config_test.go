@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %v: %v", path, err)
+	}
+	return path
+}
+
+func TestLoadYAML(t *testing.T) {
+	path := write(t, "putjson.yaml", `
+startToken: "[["
+endToken: "]]"
+format: yaml
+langReplace:
+  zh: ch
+rules:
+  - match: "legacy/.*"
+    startToken: "{{"
+    endToken: "}}"
+    format: json
+`)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if f.StartToken != "[[" || f.EndToken != "]]" || f.Format != "yaml" {
+		t.Fatalf("unexpected top-level fields: %+v", f)
+	}
+	if f.LangReplace["zh"] != "ch" {
+		t.Fatalf("unexpected langReplace: %+v", f.LangReplace)
+	}
+	if len(f.Rules) != 1 || f.Rules[0].Match != "legacy/.*" || f.Rules[0].Format != "json" {
+		t.Fatalf("unexpected rules: %+v", f.Rules)
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	path := write(t, "putjson.toml", `
+startToken = "[["
+endToken = "]]"
+format = "yaml"
+langReplace.zh = "ch"
+
+[[rules]]
+match = "legacy/.*"
+startToken = "{{"
+endToken = "}}"
+format = "json"
+`)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if f.StartToken != "[[" || f.EndToken != "]]" || f.Format != "yaml" {
+		t.Fatalf("unexpected top-level fields: %+v", f)
+	}
+	if f.LangReplace["zh"] != "ch" {
+		t.Fatalf("unexpected langReplace: %+v", f.LangReplace)
+	}
+	if len(f.Rules) != 1 || f.Rules[0].Match != "legacy/.*" || f.Rules[0].Format != "json" {
+		t.Fatalf("unexpected rules: %+v", f.Rules)
+	}
+}
+
+func TestUnquoteTOMLEscapes(t *testing.T) {
+	got, err := unquoteTOML(`"C:\\notes\tand \"quotes\"\n"`)
+	if err != nil {
+		t.Fatalf("unquoteTOML: %v", err)
+	}
+	if want := "C:\\notes\tand \"quotes\"\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDiscoverPath(t *testing.T) {
+	dir := t.TempDir()
+	if got := DiscoverPath(dir); got != "" {
+		t.Fatalf("expected no config found, got %v", got)
+	}
+
+	want := filepath.Join(dir, DefaultBaseName+".yaml")
+	if err := os.WriteFile(want, []byte("format: yaml\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := DiscoverPath(dir); got != want {
+		t.Fatalf("DiscoverPath: got %v, want %v", got, want)
+	}
+}
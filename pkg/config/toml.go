@@ -0,0 +1,170 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML fills f from a minimal TOML subset: flat "key = value" pairs,
+// dotted keys ("langReplace.zh = ...") to build a map, and "[[rules]]"
+// array-of-tables starting a new Rule. It covers exactly File's schema,
+// the same flat, hand-rolled approach pkg/encode/toml uses for writing
+// TOML, rather than pulling in a general-purpose TOML library.
+func parseTOML(data []byte, f *File) error {
+	var rule *Rule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "[[rules]]" {
+			f.Rules = append(f.Rules, Rule{})
+			rule = &f.Rules[len(f.Rules)-1]
+			continue
+		}
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value, got %q", lineNo, line)
+		}
+		if err := setField(f, rule, strings.TrimSpace(key), strings.TrimSpace(raw)); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// setField assigns key = raw to f, or to rule's matching field when rule
+// is non-nil (i.e. the line follows a "[[rules]]" header).
+func setField(f *File, rule *Rule, key, raw string) error {
+	if mapName, subKey, isMapKey := strings.Cut(key, "."); isMapKey {
+		value, err := unquoteTOML(raw)
+		if err != nil {
+			return err
+		}
+		target := &f.LangReplace
+		if rule != nil {
+			target = &rule.LangReplace
+		}
+		if mapName != "langReplace" {
+			return fmt.Errorf("unknown table %q", mapName)
+		}
+		if *target == nil {
+			*target = map[string]string{}
+		}
+		(*target)[subKey] = value
+		return nil
+	}
+
+	if rule != nil {
+		switch key {
+		case "match":
+			return assignString(&rule.Match, raw)
+		case "startToken":
+			return assignString(&rule.StartToken, raw)
+		case "endToken":
+			return assignString(&rule.EndToken, raw)
+		case "fileMatcher":
+			return assignString(&rule.FileMatcher, raw)
+		case "format":
+			return assignString(&rule.Format, raw)
+		default:
+			return fmt.Errorf("unknown rule key %q", key)
+		}
+	}
+
+	switch key {
+	case "input":
+		return assignString(&f.Input, raw)
+	case "output":
+		return assignString(&f.Output, raw)
+	case "fileMatcher":
+		return assignString(&f.FileMatcher, raw)
+	case "startToken":
+		return assignString(&f.StartToken, raw)
+	case "endToken":
+		return assignString(&f.EndToken, raw)
+	case "escapeToken":
+		value, err := unquoteTOML(raw)
+		if err != nil {
+			return err
+		}
+		f.EscapeToken = &value
+		return nil
+	case "outSuffix":
+		return assignString(&f.OutSuffix, raw)
+	case "format":
+		return assignString(&f.Format, raw)
+	case "v":
+		return assignBool(&f.Verbose, raw)
+	case "watch":
+		return assignBool(&f.Watch, raw)
+	case "force":
+		return assignBool(&f.Force, raw)
+	case "check":
+		return assignBool(&f.Check, raw)
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+}
+
+func assignString(dst *string, raw string) error {
+	value, err := unquoteTOML(raw)
+	if err != nil {
+		return err
+	}
+	*dst = value
+	return nil
+}
+
+func assignBool(dst **bool, raw string) error {
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return fmt.Errorf("invalid bool %q: %w", raw, err)
+	}
+	*dst = &value
+	return nil
+}
+
+// unquoteTOML strips a TOML basic string's surrounding quotes and resolves
+// its backslash escapes; the mirror image of pkg/encode/toml's quote. It
+// walks the string a single time so a literal backslash never feeds back
+// into a later escape, e.g. "C:\\notes" must unquote to `C:\notes`, not
+// `C:` followed by a stray newline.
+func unquoteTOML(raw string) (string, error) {
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return "", fmt.Errorf("expected a quoted string, got %q", raw)
+	}
+	s := raw[1 : len(raw)-1]
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("trailing backslash in %q", raw)
+		}
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '"':
+			b.WriteByte('"')
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			return "", fmt.Errorf("unknown escape %q in %q", `\`+string(s[i]), raw)
+		}
+	}
+	return b.String(), nil
+}
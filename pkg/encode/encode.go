@@ -0,0 +1,30 @@
+// Package encode defines the output format abstraction used by putjson:
+// an Entry per extracted block, and an Encoder that writes a set of
+// entries to some serialized form. Concrete formats live in subpackages
+// (json, yaml, toml, po, gomap) so new formats can be added without
+// touching the extraction pipeline.
+package encode
+
+import "io"
+
+// Entry is a single named block of text extracted from an input file.
+// Body holds the content after "@@bold@@" markup has been converted to
+// <b> tags, but before any format-specific escaping.
+type Entry struct {
+	Name string
+	Body string
+}
+
+// Encoder writes entries to w in some output format.
+type Encoder interface {
+	Encode(w io.Writer, entries []Entry) error
+}
+
+// KeyedEncoder is implemented by Encoders whose output is nested under a
+// per-file key, such as gomap's outer map key. Callers that process many
+// files through a single Encoder instance should set the key before each
+// Encode call.
+type KeyedEncoder interface {
+	Encoder
+	SetKey(key string)
+}
@@ -0,0 +1,57 @@
+// Package gomap writes entries as a compilable Go source file declaring a
+// nested translations map, for projects that want to embed translations
+// at compile time instead of reading JSON at runtime.
+package gomap
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+// Encoder writes entries as a Go source file defining:
+//
+//	var Translations = map[string]map[string]string{
+//		"<Key>": {"<entry name>": "<entry body>", ...},
+//	}
+type Encoder struct {
+	// Package is the package name declared at the top of the generated
+	// file; it defaults to "translations" when empty.
+	Package string
+	// Key is the outer map key these entries are nested under, typically
+	// the language or file these entries came from.
+	Key string
+}
+
+// NewEncoder builds a gomap Encoder with default Package and Key; set the
+// fields before calling Encode to customize them.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// SetKey sets the outer map key entries are nested under, satisfying
+// encode.KeyedEncoder.
+func (e *Encoder) SetKey(key string) {
+	e.Key = key
+}
+
+// Encode writes entries as a Go source file.
+func (e *Encoder) Encode(w io.Writer, entries []encode.Entry) error {
+	pkg := e.Package
+	if len(pkg) == 0 {
+		pkg = "translations"
+	}
+	if _, err := fmt.Fprintf(w, "package %s\n\nvar Translations = map[string]map[string]string{\n\t%q: {\n", pkg, e.Key); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "\t\t%q: %q,\n", entry.Name, entry.Body); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+	if _, err := fmt.Fprint(w, "\t},\n}\n"); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package gomap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+func TestEncodeDefaultPackage(t *testing.T) {
+	entries := []encode.Entry{{Name: "greeting", Body: "hello"}}
+
+	var buf strings.Builder
+	enc := NewEncoder()
+	enc.Key = "en"
+	if err := enc.Encode(&buf, entries); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{
+		"package translations\n",
+		`"en": {`,
+		`"greeting": "hello",`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output %q missing %q", got, want)
+		}
+	}
+}
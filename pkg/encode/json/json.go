@@ -0,0 +1,57 @@
+// Package json writes entries as putjson's original hand-built JSON
+// object shape: one "name": "body" pair per line, indented with two
+// spaces. It exists, rather than delegating to encoding/json, so the
+// default output stays byte-for-byte identical to what putjson has
+// always produced.
+package json
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+const indent = "  "
+
+// Encoder is the default putjson output format.
+type Encoder struct{}
+
+// NewEncoder builds the default JSON Encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Encode writes entries as a single JSON object.
+func (e *Encoder) Encode(w io.Writer, entries []encode.Entry) error {
+	if err := write(w, "{\n"); err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		if i > 0 {
+			if err := write(w, ",\n"); err != nil {
+				return err
+			}
+		}
+		if err := write(w, fmt.Sprintf("%v\"%v\": \"%v\"", indent, entry.Name, escape(entry.Body))); err != nil {
+			return err
+		}
+	}
+	return write(w, "\n}\n")
+}
+
+func write(w io.Writer, s string) error {
+	if _, err := io.WriteString(w, s); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	return nil
+}
+
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = strings.ReplaceAll(s, "\n", "\\\\n")
+	s = strings.ReplaceAll(s, "\t", "\\\\t")
+	return s
+}
@@ -0,0 +1,44 @@
+// Package po writes entries as a gettext .po catalog, with the block
+// name as msgid and the block body as msgstr. Because putjson's input
+// filenames already carry a language code, one .po file per language
+// plugs directly into standard gettext toolchains.
+package po
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+// Encoder writes entries as a gettext .po catalog.
+type Encoder struct{}
+
+// NewEncoder builds a po Encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Encode writes entries as msgid/msgstr pairs.
+func (e *Encoder) Encode(w io.Writer, entries []encode.Entry) error {
+	for i, entry := range entries {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return fmt.Errorf("write: %w", err)
+			}
+		}
+		if _, err := fmt.Fprintf(w, "msgid %s\nmsgstr %s\n", quote(entry.Name), quote(entry.Body)); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+	return nil
+}
+
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	s = strings.ReplaceAll(s, "\t", "\\t")
+	return "\"" + s + "\""
+}
@@ -0,0 +1,65 @@
+package po
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+func TestEncode(t *testing.T) {
+	entries := []encode.Entry{
+		{Name: "block_0", Body: "hello"},
+		{Name: "block_1", Body: "a \"quote\"\nand\ttab"},
+	}
+
+	var buf strings.Builder
+	if err := NewEncoder().Encode(&buf, entries); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	want := `msgid "block_0"` + "\n" + `msgstr "hello"` + "\n" +
+		"\n" +
+		`msgid "block_1"` + "\n" + `msgstr "a \"quote\"\nand\ttab"` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// TestEncodeProducesValidGettextShape checks the structural rules gettext
+// tooling expects of a .po catalog: each record is an "msgid" line
+// immediately followed by an "msgstr" line, and records are separated by
+// exactly one blank line.
+func TestEncodeProducesValidGettextShape(t *testing.T) {
+	entries := []encode.Entry{
+		{Name: "block_0", Body: "hello"},
+		{Name: "block_1", Body: "world"},
+		{Name: "block_2", Body: "!"},
+	}
+
+	var buf strings.Builder
+	if err := NewEncoder().Encode(&buf, entries); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines)%3 != 2 {
+		t.Fatalf("expected msgid/msgstr/blank triples separated by blank lines, got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		switch i % 3 {
+		case 0:
+			if !strings.HasPrefix(line, `msgid "`) {
+				t.Fatalf("line %d: expected msgid, got %q", i, line)
+			}
+		case 1:
+			if !strings.HasPrefix(line, `msgstr "`) {
+				t.Fatalf("line %d: expected msgstr, got %q", i, line)
+			}
+		case 2:
+			if line != "" {
+				t.Fatalf("line %d: expected blank separator, got %q", i, line)
+			}
+		}
+	}
+}
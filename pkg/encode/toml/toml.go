@@ -0,0 +1,37 @@
+// Package toml writes entries as a flat TOML document, one "name = value"
+// key/value pair per line using TOML basic strings.
+package toml
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+// Encoder writes entries as TOML.
+type Encoder struct{}
+
+// NewEncoder builds a TOML Encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Encode writes entries as flat TOML key/value pairs.
+func (e *Encoder) Encode(w io.Writer, entries []encode.Entry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%s = %s\n", entry.Name, quote(entry.Body)); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+	return nil
+}
+
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	s = strings.ReplaceAll(s, "\t", "\\t")
+	return "\"" + s + "\""
+}
@@ -0,0 +1,73 @@
+package toml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+func TestEncode(t *testing.T) {
+	entries := []encode.Entry{
+		{Name: "block_0", Body: "hello"},
+		{Name: "block_1", Body: "a \"quote\"\nand\ttab"},
+	}
+
+	var buf strings.Builder
+	if err := NewEncoder().Encode(&buf, entries); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	want := `block_0 = "hello"` + "\n" +
+		`block_1 = "a \"quote\"\nand\ttab"` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// TestEncodeQuotingRoundTrips decodes quote's escapes with the same
+// single-pass algorithm pkg/config uses to read TOML strings back, so a
+// regression in one side's escape handling shows up here too.
+func TestEncodeQuotingRoundTrips(t *testing.T) {
+	for _, body := range []string{
+		`hello`,
+		"a \"quote\"\nand\ttab",
+		`C:\notes`,
+	} {
+		quoted := quote(body)
+		got, err := unquote(quoted)
+		if err != nil {
+			t.Fatalf("unquote(%q): %v", quoted, err)
+		}
+		if got != body {
+			t.Fatalf("round-trip mismatch: quote(%q) = %q, unquote gave %q", body, quoted, got)
+		}
+	}
+}
+
+// unquote mirrors pkg/config's unquoteTOML: a single-pass walk that
+// consumes two characters per recognized escape.
+func unquote(raw string) (string, error) {
+	s := raw[1 : len(raw)-1]
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case '"':
+			b.WriteByte('"')
+		case '\\':
+			b.WriteByte('\\')
+		}
+	}
+	return b.String(), nil
+}
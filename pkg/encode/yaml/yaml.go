@@ -0,0 +1,38 @@
+// Package yaml writes entries as a flat YAML mapping of name to body,
+// using double-quoted scalars so no special characters need block-style
+// handling.
+package yaml
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+// Encoder writes entries as YAML.
+type Encoder struct{}
+
+// NewEncoder builds a YAML Encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Encode writes entries as a flat YAML mapping.
+func (e *Encoder) Encode(w io.Writer, entries []encode.Entry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", quote(entry.Name), quote(entry.Body)); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+	return nil
+}
+
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	s = strings.ReplaceAll(s, "\t", "\\t")
+	return "\"" + s + "\""
+}
@@ -0,0 +1,26 @@
+package yaml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+)
+
+func TestEncode(t *testing.T) {
+	entries := []encode.Entry{
+		{Name: "block_0", Body: "hello"},
+		{Name: "block_1", Body: "a \"quote\"\nand\ttab"},
+	}
+
+	var buf strings.Builder
+	if err := NewEncoder().Encode(&buf, entries); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	want := `"block_0": "hello"` + "\n" +
+		`"block_1": "a \"quote\"\nand\ttab"` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+// Package lex implements the block lexer and parser used to extract
+// translatable text blocks from an input file. It replaces a simple
+// character-walking scanner with a real tokenizer so that nested and
+// escaped delimiters, as well as named blocks, can be handled correctly.
+package lex
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// TokenType identifies the kind of a Token produced by the Lexer.
+type TokenType int
+
+const (
+	// TokenText is a run of plain content, outside or inside a block.
+	TokenText TokenType = iota
+	// TokenStartBlock is the configured start delimiter, e.g. "{{".
+	TokenStartBlock
+	// TokenEndBlock is the configured end delimiter, e.g. "}}".
+	TokenEndBlock
+	// TokenEscape is a delimiter preceded by the escape sequence; its
+	// Value is the delimiter text with the escape sequence stripped.
+	TokenEscape
+)
+
+// Token is a single lexical unit produced by the Lexer, tagged with the
+// 1-based line and column of its first byte.
+type Token struct {
+	Type   TokenType
+	Value  string
+	Line   int
+	Col    int
+	Offset int
+}
+
+// Lexer consumes a []byte and yields a stream of Tokens. It has no
+// knowledge of blocks or names; that is the Parser's job.
+type Lexer struct {
+	content     []byte
+	start, end  []byte
+	escapeStart []byte
+	escapeEnd   []byte
+	hasEscape   bool
+	pos         int
+	line, col   int
+}
+
+// NewLexer builds a Lexer over content using start/end as the block
+// delimiters. escape, when non-empty, marks a delimiter occurrence as
+// literal text, e.g. escape "\\" turns "\\{{" into a literal "{{".
+func NewLexer(content []byte, start, end, escape string) *Lexer {
+	l := &Lexer{
+		content: content,
+		start:   []byte(start),
+		end:     []byte(end),
+		line:    1,
+		col:     1,
+	}
+	if len(escape) > 0 {
+		l.hasEscape = true
+		l.escapeStart = []byte(escape + start)
+		l.escapeEnd = []byte(escape + end)
+	}
+	return l
+}
+
+// Next returns the next Token, or ok=false once the content is exhausted.
+func (l *Lexer) Next() (Token, bool) {
+	if l.pos >= len(l.content) {
+		return Token{}, false
+	}
+
+	line, col, offset := l.line, l.col, l.pos
+
+	if l.hasEscape && l.hasPrefix(l.escapeStart) {
+		l.advance(len(l.escapeStart))
+		return Token{Type: TokenEscape, Value: string(l.start), Line: line, Col: col, Offset: offset}, true
+	}
+	if l.hasEscape && l.hasPrefix(l.escapeEnd) {
+		l.advance(len(l.escapeEnd))
+		return Token{Type: TokenEscape, Value: string(l.end), Line: line, Col: col, Offset: offset}, true
+	}
+	if l.hasPrefix(l.start) {
+		l.advance(len(l.start))
+		return Token{Type: TokenStartBlock, Value: string(l.start), Line: line, Col: col, Offset: offset}, true
+	}
+	if l.hasPrefix(l.end) {
+		l.advance(len(l.end))
+		return Token{Type: TokenEndBlock, Value: string(l.end), Line: line, Col: col, Offset: offset}, true
+	}
+
+	textStart := l.pos
+	for l.pos < len(l.content) {
+		if l.hasPrefix(l.start) || l.hasPrefix(l.end) ||
+			(l.hasEscape && (l.hasPrefix(l.escapeStart) || l.hasPrefix(l.escapeEnd))) {
+			break
+		}
+		_, size := utf8.DecodeRune(l.content[l.pos:])
+		l.advance(size)
+	}
+	return Token{Type: TokenText, Value: string(l.content[textStart:l.pos]), Line: line, Col: col, Offset: offset}, true
+}
+
+func (l *Lexer) hasPrefix(tok []byte) bool {
+	return len(tok) > 0 && bytes.HasPrefix(l.content[l.pos:], tok)
+}
+
+// advance consumes n bytes, tracking line/col by rune rather than by byte
+// so multi-byte UTF-8 content reports accurate columns.
+func (l *Lexer) advance(n int) {
+	end := l.pos + n
+	if end > len(l.content) {
+		end = len(l.content)
+	}
+	for l.pos < end {
+		r, size := utf8.DecodeRune(l.content[l.pos:end])
+		if r == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.pos += size
+	}
+}
@@ -0,0 +1,76 @@
+package lex
+
+import "testing"
+
+func TestLexerBasic(t *testing.T) {
+	content := []byte("a{{bc}}d")
+	l := NewLexer(content, "{{", "}}", "")
+
+	want := []Token{
+		{Type: TokenText, Value: "a", Line: 1, Col: 1, Offset: 0},
+		{Type: TokenStartBlock, Value: "{{", Line: 1, Col: 2, Offset: 1},
+		{Type: TokenText, Value: "bc", Line: 1, Col: 4, Offset: 3},
+		{Type: TokenEndBlock, Value: "}}", Line: 1, Col: 6, Offset: 5},
+		{Type: TokenText, Value: "d", Line: 1, Col: 8, Offset: 7},
+	}
+
+	for i, w := range want {
+		got, ok := l.Next()
+		if !ok {
+			t.Fatalf("token %d: unexpected end of input", i)
+		}
+		if got != w {
+			t.Fatalf("token %d: got %+v, want %+v", i, got, w)
+		}
+	}
+	if _, ok := l.Next(); ok {
+		t.Fatal("expected end of input")
+	}
+}
+
+func TestLexerEscape(t *testing.T) {
+	content := []byte(`\{{no block\}}`)
+	l := NewLexer(content, "{{", "}}", `\`)
+
+	want := []TokenType{TokenEscape, TokenText, TokenEscape}
+	for i, wantType := range want {
+		got, ok := l.Next()
+		if !ok {
+			t.Fatalf("token %d: unexpected end of input", i)
+		}
+		if got.Type != wantType {
+			t.Fatalf("token %d: got type %v, want %v", i, got.Type, wantType)
+		}
+	}
+	if got, ok := l.Next(); ok {
+		t.Fatalf("expected end of input, got %+v", got)
+	}
+}
+
+func TestLexerColCountsRunesNotBytes(t *testing.T) {
+	content := []byte("日本語{{c}}")
+	l := NewLexer(content, "{{", "}}", "")
+
+	_, _ = l.Next() // "日本語"
+	start, ok := l.Next()
+	if !ok {
+		t.Fatal("expected start token")
+	}
+	if start.Type != TokenStartBlock || start.Line != 1 || start.Col != 4 {
+		t.Fatalf("got %+v, want start block at 1:4", start)
+	}
+}
+
+func TestLexerLineCol(t *testing.T) {
+	content := []byte("a\nb{{c}}")
+	l := NewLexer(content, "{{", "}}", "")
+
+	_, _ = l.Next() // "a\nb"
+	start, ok := l.Next()
+	if !ok {
+		t.Fatal("expected start token")
+	}
+	if start.Type != TokenStartBlock || start.Line != 2 || start.Col != 2 {
+		t.Fatalf("got %+v, want start block at 2:2", start)
+	}
+}
@@ -0,0 +1,139 @@
+package lex
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// namedBlockHeader matches an optional "{{@name: ...}}" header at the very
+// start of a block body, so callers can key output entries by a meaningful
+// name instead of a generated one. The leading "@" is required so that
+// ordinary prose starting with a word and a colon (e.g. "Error: file not
+// found") is never mistaken for a name.
+var namedBlockHeader = regexp.MustCompile(`^@([A-Za-z_][A-Za-z0-9_]*):[ \t]?`)
+
+// Block is a single extracted translatable block.
+type Block struct {
+	// Name is the explicit block name from a "{{@name: ...}}" header, or
+	// empty when the block is unnamed and the caller must fall back to a
+	// generated name.
+	Name string
+	Body string
+	Line int
+	Col  int
+}
+
+// ParseError reports a malformed block with its source position.
+type ParseError struct {
+	Message string
+	Line    int
+	Col     int
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Col, e.Message)
+}
+
+// Parser turns a token stream into a flat list of Blocks. Nested start
+// delimiters are tracked by depth so a block may contain the start
+// delimiter as long as it is balanced by a matching end.
+type Parser struct {
+	lexer *Lexer
+}
+
+// NewParser builds a Parser over content using start/end as the block
+// delimiters and escape as the escape sequence (see NewLexer).
+func NewParser(content []byte, start, end, escape string) *Parser {
+	return &Parser{lexer: NewLexer(content, start, end, escape)}
+}
+
+// Parse consumes the whole input and returns every well-formed block found,
+// along with one ParseError per malformed occurrence (an end without a
+// matching start, or an unclosed block at end of input).
+func (p *Parser) Parse() ([]Block, []error) {
+	var (
+		blocks              []Block
+		errs                []error
+		depth               int
+		body                strings.Builder
+		blockLine, blockCol int
+	)
+
+	for {
+		tok, ok := p.lexer.Next()
+		if !ok {
+			break
+		}
+
+		switch tok.Type {
+		case TokenStartBlock:
+			if depth == 0 {
+				blockLine, blockCol = tok.Line, tok.Col
+				body.Reset()
+			} else {
+				body.WriteString(tok.Value)
+			}
+			depth++
+		case TokenEndBlock:
+			if depth == 0 {
+				errs = append(errs, &ParseError{
+					Message: fmt.Sprintf("detected end block but without predefined start, near %q", near(p.lexer.content, tok.Offset)),
+					Line:    tok.Line,
+					Col:     tok.Col,
+				})
+				continue
+			}
+			depth--
+			if depth == 0 {
+				blocks = append(blocks, newBlock(body.String(), blockLine, blockCol))
+			} else {
+				body.WriteString(tok.Value)
+			}
+		case TokenEscape, TokenText:
+			if depth > 0 {
+				body.WriteString(tok.Value)
+			}
+		}
+	}
+
+	if depth > 0 {
+		errs = append(errs, &ParseError{
+			Message: "detected start block but previous start is not closed before end of input",
+			Line:    blockLine,
+			Col:     blockCol,
+		})
+	}
+
+	return blocks, errs
+}
+
+func newBlock(raw string, line, col int) Block {
+	if m := namedBlockHeader.FindStringSubmatchIndex(raw); m != nil {
+		return Block{
+			Name: raw[m[2]:m[3]],
+			Body: raw[m[1]:],
+			Line: line,
+			Col:  col,
+		}
+	}
+	return Block{Body: raw, Line: line, Col: col}
+}
+
+// near returns a short snippet of content around offset, for error messages.
+func near(content []byte, offset int) string {
+	const radius = 10
+	from := offset - radius
+	to := offset + radius
+	if from < 0 {
+		to -= from
+		from = 0
+	}
+	if to > len(content) {
+		to = len(content)
+	}
+	if from > len(content) {
+		from = len(content)
+	}
+	return string(content[from:to])
+}
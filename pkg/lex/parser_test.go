@@ -0,0 +1,91 @@
+package lex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParserUnnamedBlocks(t *testing.T) {
+	content := []byte("before{{one}}mid{{two}}after")
+	blocks, errs := NewParser(content, "{{", "}}", `\`).Parse()
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := []Block{
+		{Body: "one", Line: 1, Col: 7},
+		{Body: "two", Line: 1, Col: 17},
+	}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Fatalf("got %+v, want %+v", blocks, want)
+	}
+}
+
+func TestParserNamedBlock(t *testing.T) {
+	content := []byte("{{@greeting: hello}}")
+	blocks, errs := NewParser(content, "{{", "}}", `\`).Parse()
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := []Block{{Name: "greeting", Body: "hello", Line: 1, Col: 1}}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Fatalf("got %+v, want %+v", blocks, want)
+	}
+}
+
+func TestParserProseWithColonIsNotMistakenForName(t *testing.T) {
+	content := []byte("{{Error: File not found, please try again.}}")
+	blocks, errs := NewParser(content, "{{", "}}", `\`).Parse()
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := []Block{{Body: "Error: File not found, please try again.", Line: 1, Col: 1}}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Fatalf("got %+v, want %+v", blocks, want)
+	}
+}
+
+func TestParserNestedDelimiters(t *testing.T) {
+	content := []byte("{{outer {{inner}} still outer}}")
+	blocks, errs := NewParser(content, "{{", "}}", `\`).Parse()
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := []Block{{Body: "outer {{inner}} still outer", Line: 1, Col: 1}}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Fatalf("got %+v, want %+v", blocks, want)
+	}
+}
+
+func TestParserEscapedDelimiters(t *testing.T) {
+	content := []byte(`{{has \{{escaped\}} delimiters}}`)
+	blocks, errs := NewParser(content, "{{", "}}", `\`).Parse()
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := []Block{{Body: "has {{escaped}} delimiters", Line: 1, Col: 1}}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Fatalf("got %+v, want %+v", blocks, want)
+	}
+}
+
+func TestParserUnmatchedEnd(t *testing.T) {
+	content := []byte("text}}more")
+	blocks, errs := NewParser(content, "{{", "}}", `\`).Parse()
+	if len(blocks) != 0 {
+		t.Fatalf("expected no blocks, got %+v", blocks)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+}
+
+func TestParserUnclosedBlock(t *testing.T) {
+	content := []byte("{{never closed")
+	blocks, errs := NewParser(content, "{{", "}}", `\`).Parse()
+	if len(blocks) != 0 {
+		t.Fatalf("expected no blocks, got %+v", blocks)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+}
@@ -0,0 +1,565 @@
+// Package putjson is the extraction engine behind the putjson tool: it
+// walks a tree of input files, pulls out delimited translatable blocks
+// with pkg/lex, and writes them out with a pkg/encode Encoder. It is
+// exposed as a library, rather than only a CLI, so build tools, tests and
+// code generators can embed the extractor directly — including running it
+// against an in-memory fs.FS instead of the real filesystem.
+package putjson
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/m4gshm/putjson/pkg/encode"
+	"github.com/m4gshm/putjson/pkg/lex"
+	"github.com/m4gshm/putjson/pkg/sum"
+)
+
+// DefaultOutExt is the output file extension used when Config.OutExt is
+// empty.
+const DefaultOutExt = ".json"
+
+// Config configures a single extraction Run.
+type Config struct {
+	// Input is the root directory to read input files from.
+	Input string
+	// Output is the root directory output files and putjson.sum are
+	// written to.
+	Output string
+	// FileMatcher is a regexp matched against each input file's base
+	// name; a "language" capture group, if present, selects the output
+	// language directory.
+	FileMatcher string
+	StartToken  string
+	EndToken    string
+	// EscapeToken, when non-empty, is the prefix that escapes a
+	// start/end token so it is treated as literal text.
+	EscapeToken string
+	// OutSuffix is appended to an input file's directory when building
+	// its output directory.
+	OutSuffix string
+	// OutExt is the output file extension, e.g. ".json"; it defaults to
+	// DefaultOutExt when empty.
+	OutExt string
+	// LangReplace maps a matched language code to a replacement, e.g.
+	// "zh" -> "ch".
+	LangReplace map[string]string
+	// Logger receives progress and diagnostics; it defaults to
+	// slog.Default() when nil. Verbose output is logged at slog.LevelDebug.
+	Logger *slog.Logger
+	// FS is read from to find and load input files; it defaults to
+	// os.DirFS(Input) when nil, which lets callers substitute an
+	// in-memory fs.FS (e.g. fstest.MapFS) to run without touching disk.
+	FS fs.FS
+	// Encoder writes each file's extracted blocks in the desired output
+	// format. If it implements encode.KeyedEncoder, its key is set to
+	// each output file's path (sans extension) before Encode is called.
+	Encoder encode.Encoder
+	// Force reprocesses every file, bypassing the putjson.sum cache.
+	Force bool
+	// Check verifies that on-disk outputs already match what would be
+	// generated, without writing anything; stale outputs are reported
+	// via Report.CheckFailures.
+	Check bool
+	// Rules overrides a subset of this Config's fields for input files
+	// whose path (relative to Input) matches Rule.Match, letting one Run
+	// handle a tree that mixes tokens, file-matchers, language
+	// replacers, or output formats across subdirectories. Rules are
+	// applied in order, so a later rule wins over an earlier one for any
+	// field both set on the same file.
+	Rules []Rule
+}
+
+// Rule overrides a subset of Config's fields for input files matching
+// Match. A zero field is left at the enclosing Config's (or earlier
+// rule's) value.
+type Rule struct {
+	// Match is evaluated as a regexp against the input file's path
+	// relative to Input; if Match fails to compile as a regexp, it is
+	// tried as a filepath.Match glob instead.
+	Match string
+	// StartToken and EndToken override Config.StartToken/EndToken.
+	StartToken string
+	EndToken   string
+	// FileMatcher overrides Config.FileMatcher.
+	FileMatcher string
+	// LangReplace overrides Config.LangReplace.
+	LangReplace map[string]string
+	// Encoder and OutExt override Config.Encoder/Config.OutExt, e.g. to
+	// extract one subtree as YAML while the rest of the tree stays JSON.
+	Encoder encode.Encoder
+	OutExt  string
+}
+
+// resolvedRule is a Rule with its Match pattern pre-compiled so Run
+// doesn't recompile it for every file it walks.
+type resolvedRule struct {
+	Rule
+	match  *regexp.Regexp
+	fileRe *regexp.Regexp
+}
+
+func resolveRules(rules []Rule) ([]resolvedRule, error) {
+	resolved := make([]resolvedRule, len(rules))
+	for i, r := range rules {
+		if len(r.Match) == 0 {
+			return nil, fmt.Errorf("rule %d: match must be defined", i)
+		}
+		rr := resolvedRule{Rule: r}
+		if re, err := regexp.Compile(r.Match); err == nil {
+			rr.match = re
+		} else if _, globErr := filepath.Match(r.Match, ""); globErr != nil {
+			return nil, fmt.Errorf("rule %d: match %q is neither a valid regexp (%v) nor a valid glob: %w", i, r.Match, err, globErr)
+		}
+		if len(r.FileMatcher) > 0 {
+			re, err := regexp.Compile(r.FileMatcher)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: invalid fileMatcher %v: %w", i, r.FileMatcher, err)
+			}
+			rr.fileRe = re
+		}
+		resolved[i] = rr
+	}
+	return resolved, nil
+}
+
+func (rr resolvedRule) matches(relPath string) bool {
+	if rr.match != nil {
+		return rr.match.MatchString(relPath)
+	}
+	ok, _ := filepath.Match(rr.Match, relPath)
+	return ok
+}
+
+// applyRules returns cfg and re with every matching rule's overrides
+// folded in, in rule order, so a later rule wins over an earlier one.
+func applyRules(cfg Config, re *regexp.Regexp, rules []resolvedRule, relPath string) (Config, *regexp.Regexp) {
+	for _, rr := range rules {
+		if !rr.matches(relPath) {
+			continue
+		}
+		if len(rr.StartToken) > 0 {
+			cfg.StartToken = rr.StartToken
+		}
+		if len(rr.EndToken) > 0 {
+			cfg.EndToken = rr.EndToken
+		}
+		if len(rr.LangReplace) > 0 {
+			cfg.LangReplace = rr.LangReplace
+		}
+		if rr.Encoder != nil {
+			cfg.Encoder = rr.Encoder
+		}
+		if len(rr.OutExt) > 0 {
+			cfg.OutExt = rr.OutExt
+		}
+		if rr.fileRe != nil {
+			re = rr.fileRe
+		}
+	}
+	return cfg, re
+}
+
+// rulesKey turns Rules into a stable string so it can be folded into the
+// config hash.
+func rulesKey(rules []Rule) string {
+	parts := make([]string, len(rules))
+	for i, r := range rules {
+		parts[i] = strings.Join([]string{
+			r.Match, r.StartToken, r.EndToken, r.FileMatcher, langReplaceKey(r.LangReplace), r.OutExt, fmt.Sprintf("%T", r.Encoder),
+		}, "\x01")
+	}
+	return strings.Join(parts, "\x02")
+}
+
+// FileReport summarizes the processing of a single input file.
+type FileReport struct {
+	Input       string
+	Output      string
+	Blocks      int
+	ParseErrors []error
+	// Skipped is true when the file was unchanged since the last Run and
+	// its cached output was reused.
+	Skipped bool
+	// Stale is true, in Check mode, when the on-disk output does not
+	// match what would be generated.
+	Stale bool
+}
+
+// Mismatch records a directory whose files declared inconsistent block
+// counts, e.g. a translation missing a block present in its sibling
+// languages.
+type Mismatch struct {
+	Dir      string
+	File     string
+	Expected int
+	Actual   int
+}
+
+// Report is the outcome of a Run.
+type Report struct {
+	Files      []FileReport
+	Mismatches []Mismatch
+	// Removed lists output paths deleted because their input no longer
+	// exists.
+	Removed []string
+	// StaleOrphans lists, in Check mode, output paths that would have been
+	// removed because their input no longer exists; CheckFailures reports
+	// these as failures too, since a stale orphan is still out of date.
+	StaleOrphans []string
+}
+
+// CheckFailures returns the output paths Report found stale during a
+// Check run, including both outputs whose content no longer matches their
+// input and orphaned outputs whose input was deleted.
+func (r Report) CheckFailures() []string {
+	var stale []string
+	for _, f := range r.Files {
+		if f.Stale {
+			stale = append(stale, f.Output)
+		}
+	}
+	return append(stale, r.StaleOrphans...)
+}
+
+// Run extracts translatable blocks from every file under cfg.Input whose
+// name matches cfg.FileMatcher and writes them under cfg.Output with
+// cfg.Encoder, with cfg.Rules overriding those settings for paths they
+// match. It is incremental: a putjson.sum file at cfg.Output records each
+// input's content hash, so a Run that follows skips inputs that haven't
+// changed, and removes the previous outputs of inputs that have since
+// disappeared.
+func Run(ctx context.Context, cfg Config) (Report, error) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	if cfg.StartToken == cfg.EndToken {
+		return Report{}, fmt.Errorf("start block %v must be different with end one %v", cfg.StartToken, cfg.EndToken)
+	}
+	if len(cfg.FileMatcher) == 0 {
+		return Report{}, fmt.Errorf("fileMatcher regexp must be defined")
+	}
+	re, err := regexp.Compile(cfg.FileMatcher)
+	if err != nil {
+		return Report{}, fmt.Errorf("invalid fileMatcher %v: %w", cfg.FileMatcher, err)
+	}
+	rules, err := resolveRules(cfg.Rules)
+	if err != nil {
+		return Report{}, err
+	}
+	if cfg.Encoder == nil {
+		return Report{}, fmt.Errorf("encoder must be defined")
+	}
+	if len(cfg.Input) == 0 {
+		return Report{}, fmt.Errorf("input dir not defined")
+	}
+	if len(cfg.Output) == 0 {
+		return Report{}, fmt.Errorf("output dir not defined")
+	}
+
+	outExt := cfg.OutExt
+	if len(outExt) == 0 {
+		outExt = DefaultOutExt
+	}
+
+	inFS := cfg.FS
+	if inFS == nil {
+		if err := os.MkdirAll(cfg.Input, os.ModePerm); err != nil {
+			return Report{}, fmt.Errorf("error of create input dir %v: %v", cfg.Input, err)
+		}
+		inFS = os.DirFS(cfg.Input)
+	}
+
+	sumPath := filepath.Join(cfg.Output, sum.FileName)
+	cachedConfigHash, cachedEntries, err := sum.Load(sumPath)
+	if err != nil {
+		return Report{}, err
+	}
+	currentConfigHash := sum.HashConfig(cfg.StartToken, cfg.EndToken, cfg.EscapeToken, outExt, cfg.OutSuffix, cfg.FileMatcher, langReplaceKey(cfg.LangReplace), rulesKey(cfg.Rules))
+	if cachedConfigHash != currentConfigHash {
+		if len(cachedEntries) > 0 {
+			logger.Debug("putjson config changed, invalidating cache", "file", sum.FileName)
+		}
+		cachedEntries = nil
+	}
+
+	newEntries := make(sum.File, len(cachedEntries))
+	visited := make(map[string]bool, len(cachedEntries))
+	inputDirStatistic := make(map[string]int)
+	cfg.OutExt = outExt
+
+	var report Report
+
+	err = fs.WalkDir(inFS, ".", func(relPath string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		fileCfg, fileRe := applyRules(cfg, re, rules, relPath)
+		if !fileRe.MatchString(d.Name()) {
+			return nil
+		}
+		fr, fileErr := processFile(fileCfg, logger, inFS, fileRe, cachedEntries,
+			newEntries, inputDirStatistic, &report.Mismatches, relPath)
+		if fileErr != nil {
+			return fileErr
+		}
+		visited[relPath] = true
+		report.Files = append(report.Files, *fr)
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("walk %v: %w", cfg.Input, err)
+	}
+
+	for relPath, entry := range cachedEntries {
+		if visited[relPath] {
+			continue
+		}
+		for _, out := range entry.Outputs {
+			if cfg.Check {
+				report.StaleOrphans = append(report.StaleOrphans, out)
+				logger.Error("stale output would be removed", "input", relPath, "output", out)
+				continue
+			}
+			if removeErr := os.Remove(out); removeErr != nil && !os.IsNotExist(removeErr) {
+				logger.Error("remove stale output", "output", out, "error", removeErr)
+				continue
+			}
+			report.Removed = append(report.Removed, out)
+			logger.Info("removed stale output", "input", relPath, "output", out)
+		}
+	}
+
+	if !cfg.Check {
+		if err := sum.Save(sumPath, currentConfigHash, newEntries); err != nil {
+			logger.Error("write sum file", "path", sumPath, "error", err)
+		}
+	}
+
+	return report, nil
+}
+
+// resolveOutputPath builds file's output path: its "language" capture
+// group under re, if any, selects the output language directory (after
+// langReplace substitution), outSuffix is appended to its input
+// directory, and outExt replaces its input extension.
+func resolveOutputPath(file string, re *regexp.Regexp, outSuffix, outExt string, langReplace map[string]string, logger *slog.Logger) string {
+	dir := filepath.Dir(file)
+	fileName := filepath.Base(file)
+
+	noOutDir := len(dir) == 0 || dir == "."
+	outFileName := dir
+	if noOutDir {
+		outFileName = filepath.Base(file)
+		ext := filepath.Ext(file)
+		if len(ext) > 0 {
+			outFileName = file[:len(file)-len(ext)]
+		}
+	}
+	submatches := re.FindAllStringSubmatch(fileName, -1)
+
+	outFilePath := file
+	for _, subMatch := range submatches {
+		for i, subExpName := range re.SubexpNames() {
+			if subExpName == "language" {
+				lang := subMatch[i]
+				if replacer, ok := langReplace[lang]; ok {
+					logger.Debug("replace lang", "from", lang, "to", replacer)
+					lang = replacer
+				}
+				outFilePath = filepath.Join(lang, outFileName)
+				break
+			}
+		}
+	}
+
+	if !noOutDir {
+		dir = dir + outSuffix
+	}
+	return filepath.Join(dir, outFilePath+outExt)
+}
+
+// processFile extracts and writes (or, in Check mode, verifies) the
+// blocks of a single input file.
+func processFile(
+	cfg Config,
+	logger *slog.Logger,
+	inFS fs.FS,
+	re *regexp.Regexp,
+	cachedEntries sum.File,
+	newEntries sum.File,
+	inputDirStatistic map[string]int,
+	mismatches *[]Mismatch,
+	relPath string,
+) (*FileReport, error) {
+	raw, err := fs.ReadFile(inFS, relPath)
+	if err != nil {
+		logger.Error("read file", "path", relPath, "error", err)
+		return &FileReport{Input: relPath}, nil
+	}
+
+	outExt := cfg.OutExt
+	outFileName := resolveOutputPath(relPath, re, cfg.OutSuffix, outExt, cfg.LangReplace, logger)
+	outFilePath := filepath.Join(cfg.Output, outFileName)
+	keyedEncoder, _ := cfg.Encoder.(encode.KeyedEncoder)
+	inputDir := filepath.Dir(relPath)
+	hash := sum.HashContent(raw)
+
+	report := &FileReport{Input: relPath, Output: outFilePath}
+
+	if !cfg.Force && !cfg.Check {
+		if cached, ok := cachedEntries[relPath]; ok && cached.UpToDate(hash) {
+			logger.Debug("unchanged, skipping", "path", relPath)
+			newEntries[relPath] = cached
+			report.Skipped = true
+			report.Blocks = cached.Blocks
+			recordDirStatistic(inputDirStatistic, mismatches, logger, inputDir, relPath, cached.Blocks)
+			return report, nil
+		}
+	}
+
+	blocks, parseErrors := lex.NewParser(raw, cfg.StartToken, cfg.EndToken, cfg.EscapeToken).Parse()
+	for _, parseErr := range parseErrors {
+		logger.Error("parse error", "path", relPath, "error", parseErr)
+	}
+	report.Blocks = len(blocks)
+	report.ParseErrors = parseErrors
+
+	if len(blocks) == 0 {
+		return report, nil
+	}
+
+	numberRank := 1
+	for rem := len(blocks) / 10; rem > 0; rem = rem / 10 {
+		numberRank++
+	}
+
+	seenNames := make(map[string]bool, len(blocks))
+	entries := make([]encode.Entry, len(blocks))
+	for i, b := range blocks {
+		blockName := b.Name
+		tmpl := "block_%0" + strconv.Itoa(numberRank) + "d"
+		if len(blockName) == 0 {
+			blockName = fmt.Sprintf(tmpl, i)
+		} else if seenNames[blockName] {
+			logger.Error("duplicate block name, falling back to a generated name", "path", relPath, "name", blockName)
+			blockName = fmt.Sprintf(tmpl, i)
+		}
+		seenNames[blockName] = true
+		entries[i] = encode.Entry{Name: blockName, Body: processBlock(blockName, b.Body)}
+	}
+
+	if keyedEncoder != nil {
+		keyedEncoder.SetKey(strings.TrimSuffix(outFileName, outExt))
+	}
+
+	var buf bytes.Buffer
+	if err := cfg.Encoder.Encode(&buf, entries); err != nil {
+		return report, err
+	}
+
+	if cfg.Check {
+		existing, readErr := os.ReadFile(outFilePath)
+		report.Stale = readErr != nil || !bytes.Equal(existing, buf.Bytes())
+		if report.Stale {
+			logger.Error("output is out of date", "input", relPath, "output", outFilePath)
+		}
+	} else {
+		outFileDir := filepath.Dir(outFilePath)
+		if err := os.MkdirAll(outFileDir, os.ModePerm); err != nil {
+			logger.Error("create output dir", "dir", outFileDir, "error", err)
+			return report, nil
+		}
+		if err := os.WriteFile(outFilePath, buf.Bytes(), 0o644); err != nil {
+			logger.Error("create output file", "path", outFilePath, "error", err)
+			return report, nil
+		}
+		newEntries[relPath] = sum.Entry{Hash: hash, Blocks: len(blocks), Outputs: []string{outFilePath}}
+	}
+
+	actual := len(blocks)
+	if len(parseErrors) > 0 {
+		logger.Info("processed", "input", relPath, "output", outFilePath, "blocks", actual, "errors", len(parseErrors))
+	} else {
+		logger.Debug("processed", "input", relPath, "output", outFilePath, "blocks", actual)
+	}
+	recordDirStatistic(inputDirStatistic, mismatches, logger, inputDir, relPath, actual)
+
+	return report, nil
+}
+
+// recordDirStatistic folds a file's block count into inputDirStatistic,
+// the per-directory count every sibling file is expected to match, and
+// records a Mismatch when it disagrees. It is shared by the normal
+// extraction path and the cache-skip path, so a file that was skipped
+// this run still counts toward its directory's expectation.
+func recordDirStatistic(inputDirStatistic map[string]int, mismatches *[]Mismatch, logger *slog.Logger, inputDir, relPath string, actual int) {
+	if actual == 0 {
+		return
+	}
+	if expected := inputDirStatistic[inputDir]; expected == 0 {
+		inputDirStatistic[inputDir] = actual
+	} else if expected != actual {
+		logger.Error("blocks mismatched", "dir", inputDir, "file", relPath, "expected", expected, "actual", actual)
+		*mismatches = append(*mismatches, Mismatch{Dir: inputDir, File: relPath, Expected: expected, Actual: actual})
+		if actual > expected {
+			inputDirStatistic[inputDir] = actual
+		}
+	}
+}
+
+// processBlock converts "@@bold@@" markup in a block's body into <b> tags;
+// it does not apply any output-format escaping, which is the Encoder's job.
+func processBlock(name, content string) string {
+	const tag = "@@"
+	const tagLen = len(tag)
+	out := ""
+	for blockIndex := 0; ; blockIndex++ {
+		boldPos := strings.Index(content, tag)
+		if boldPos < 0 {
+			break
+		}
+		nextPart := content[boldPos+tagLen:]
+		finishPos := strings.Index(nextPart, tag)
+		if finishPos > 0 {
+			tagContent := nextPart[0:finishPos]
+			out += content[0:boldPos] + fmt.Sprintf("<b class=\"%v_%d\">%v</b>", name, blockIndex, tagContent)
+			content = nextPart[finishPos+tagLen:]
+		}
+	}
+	out += content
+	return out
+}
+
+// langReplaceKey turns a LangReplace map into a stable string so it can be
+// folded into the config hash regardless of map iteration order.
+func langReplaceKey(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + m[k]
+	}
+	return strings.Join(parts, ",")
+}
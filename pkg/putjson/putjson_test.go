@@ -0,0 +1,253 @@
+package putjson
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	jsonenc "github.com/m4gshm/putjson/pkg/encode/json"
+	"github.com/m4gshm/putjson/pkg/encode/yaml"
+)
+
+func baseConfig(t *testing.T, fs fstest.MapFS) Config {
+	t.Helper()
+	return Config{
+		Output:      t.TempDir(),
+		FileMatcher: `(?P<language>[a-z]{2})\.txt`,
+		StartToken:  "{{",
+		EndToken:    "}}",
+		Encoder:     jsonenc.NewEncoder(),
+		FS:          fs,
+		Input:       ".",
+	}
+}
+
+func TestRunInMemoryFS(t *testing.T) {
+	in := fstest.MapFS{
+		"en.txt": {Data: []byte("hello {{greeting: world}}!")},
+	}
+	cfg := baseConfig(t, in)
+
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Files) != 1 || report.Files[0].Blocks != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	out := filepath.Join(cfg.Output, "en", "en.json")
+	if _, err := os.Stat(out); err != nil {
+		t.Fatalf("expected output at %v: %v", out, err)
+	}
+}
+
+func TestRunSkipsUnchanged(t *testing.T) {
+	in := fstest.MapFS{
+		"en.txt": {Data: []byte("{{a: 1}}")},
+	}
+	cfg := baseConfig(t, in)
+
+	if _, err := Run(context.Background(), cfg); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if len(report.Files) != 1 || !report.Files[0].Skipped {
+		t.Fatalf("expected cached skip, got %+v", report.Files)
+	}
+}
+
+func TestRunRemovesOrphanedOutput(t *testing.T) {
+	in := fstest.MapFS{
+		"en.txt": {Data: []byte("{{a: 1}}")},
+	}
+	cfg := baseConfig(t, in)
+
+	if _, err := Run(context.Background(), cfg); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	out := filepath.Join(cfg.Output, "en", "en.json")
+	if _, err := os.Stat(out); err != nil {
+		t.Fatalf("expected output before removal: %v", err)
+	}
+
+	delete(in, "en.txt")
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if len(report.Removed) != 1 || report.Removed[0] != out {
+		t.Fatalf("expected %v removed, got %+v", out, report.Removed)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Fatalf("expected output gone, stat err=%v", err)
+	}
+}
+
+func TestRunCheckDoesNotRemoveOrphanedOutput(t *testing.T) {
+	in := fstest.MapFS{
+		"en.txt": {Data: []byte("{{a: 1}}")},
+	}
+	cfg := baseConfig(t, in)
+
+	if _, err := Run(context.Background(), cfg); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	out := filepath.Join(cfg.Output, "en", "en.json")
+	if _, err := os.Stat(out); err != nil {
+		t.Fatalf("expected output before removal: %v", err)
+	}
+
+	delete(in, "en.txt")
+	cfg.Check = true
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("check Run: %v", err)
+	}
+	if len(report.Removed) != 0 {
+		t.Fatalf("check mode must not remove outputs, got %+v", report.Removed)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Fatalf("expected output to survive check mode: %v", err)
+	}
+	if failures := report.CheckFailures(); len(failures) != 1 || failures[0] != out {
+		t.Fatalf("expected the stale orphan reported as a check failure, got %+v", failures)
+	}
+}
+
+func TestRunCheckDoesNotWrite(t *testing.T) {
+	in := fstest.MapFS{
+		"en.txt": {Data: []byte("{{a: 1}}")},
+	}
+	cfg := baseConfig(t, in)
+	cfg.Check = true
+
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.CheckFailures()) != 1 {
+		t.Fatalf("expected one stale output reported, got %+v", report)
+	}
+	out := filepath.Join(cfg.Output, "en", "en.json")
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Fatalf("check mode must not write output, stat err=%v", err)
+	}
+}
+
+func TestRunDetectsMismatchAcrossSkippedSibling(t *testing.T) {
+	in := fstest.MapFS{
+		"en.txt": {Data: []byte("{{a: 1}}{{b: 2}}")},
+		"fr.txt": {Data: []byte("{{a: 1}}{{b: 2}}")},
+	}
+	cfg := baseConfig(t, in)
+
+	if _, err := Run(context.Background(), cfg); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+
+	// en.txt is left untouched so the second Run skips it via the
+	// putjson.sum cache; only fr.txt's block count actually changes.
+	in["fr.txt"] = &fstest.MapFile{Data: []byte("{{a: 1}}")}
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if len(report.Mismatches) != 1 {
+		t.Fatalf("expected a mismatch detected against the skipped sibling, got %+v", report.Mismatches)
+	}
+}
+
+func TestRunDuplicateBlockNamesFallBackToGenerated(t *testing.T) {
+	in := fstest.MapFS{
+		"en.txt": {Data: []byte("{{@note: first}} {{@note: second}}")},
+	}
+	cfg := baseConfig(t, in)
+
+	if _, err := Run(context.Background(), cfg); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	out := filepath.Join(cfg.Output, "en", "en.json")
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("unmarshal output: %v\n%s", err, data)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 distinct entries, got %+v", entries)
+	}
+}
+
+func TestRunRuleOverridesTokensByPath(t *testing.T) {
+	in := fstest.MapFS{
+		"en.txt":      {Data: []byte("{{a: 1}}")},
+		"special.txt": {Data: []byte("[[b: 2]]")},
+	}
+	cfg := baseConfig(t, in)
+	cfg.Rules = []Rule{
+		{Match: `^special\.txt$`, StartToken: "[[", EndToken: "]]"},
+	}
+
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	byInput := make(map[string]FileReport, len(report.Files))
+	for _, fr := range report.Files {
+		byInput[fr.Input] = fr
+	}
+	if got := byInput["en.txt"].Blocks; got != 1 {
+		t.Fatalf("en.txt: expected 1 block, got %d", got)
+	}
+	if got := byInput["special.txt"].Blocks; got != 1 {
+		t.Fatalf("special.txt: expected 1 block under the rule's tokens, got %d", got)
+	}
+}
+
+func TestRunRuleOverridesEncoderByGlob(t *testing.T) {
+	in := fstest.MapFS{
+		"jp.txt": {Data: []byte("{{a: 1}}")},
+	}
+	cfg := baseConfig(t, in)
+	cfg.Rules = []Rule{
+		{Match: "*.txt", Encoder: yaml.NewEncoder(), OutExt: ".yaml"},
+	}
+
+	if _, err := Run(context.Background(), cfg); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	out := filepath.Join(cfg.Output, "jp", "jp.yaml")
+	if _, err := os.Stat(out); err != nil {
+		t.Fatalf("expected rule output at %v: %v", out, err)
+	}
+}
+
+func TestRunRejectsRuleWithNoMatch(t *testing.T) {
+	in := fstest.MapFS{"en.txt": {Data: []byte("{{a: 1}}")}}
+	cfg := baseConfig(t, in)
+	cfg.Rules = []Rule{{StartToken: "[["}}
+
+	if _, err := Run(context.Background(), cfg); err == nil {
+		t.Fatal("expected an error for a rule with no match pattern")
+	}
+}
+
+func TestRunRejectsRuleWithInvalidMatch(t *testing.T) {
+	in := fstest.MapFS{"en.txt": {Data: []byte("{{a: 1}}")}}
+	cfg := baseConfig(t, in)
+	cfg.Rules = []Rule{{Match: "["}}
+
+	if _, err := Run(context.Background(), cfg); err == nil {
+		t.Fatal("expected an error for a rule whose match is neither a valid regexp nor a valid glob")
+	}
+}
@@ -0,0 +1,136 @@
+// Package sum implements the content-hash bookkeeping behind putjson's
+// incremental builds: a stable per-file hash in the style of
+// golang.org/x/mod's directory hashes, and a putjson.sum file that records
+// which input produced which output(s) at which hash.
+package sum
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileName is the conventional name of the sum file, persisted at the
+// output root.
+const FileName = "putjson.sum"
+
+// Entry records the hash an input file had when it was last processed,
+// how many blocks it produced, and the output path(s) that run produced.
+// Blocks lets a Run that skips an unchanged input still fold its block
+// count into the cross-file mismatch check.
+type Entry struct {
+	Hash    string
+	Blocks  int
+	Outputs []string
+}
+
+// File maps a relative input path to its Entry.
+type File map[string]Entry
+
+// HashContent returns a stable "h1:"-prefixed hash of content, the same
+// shape golang.org/x/mod uses for directory hashes.
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "h1:" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// HashConfig hashes the effective configuration (tokens, language
+// replacers, output format, ...) so that a sum file computed under one
+// configuration is detected as stale under another, even if no input file
+// changed.
+func HashConfig(parts ...string) string {
+	return HashContent([]byte(strings.Join(parts, "\x00")))
+}
+
+// Load reads a sum file, returning its config hash and entries. A missing
+// file is not an error; it yields an empty config hash and a nil File.
+func Load(path string) (configHash string, file File, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, nil
+		}
+		return "", nil, fmt.Errorf("open %v: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	file = make(File)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "config "); ok {
+			configHash = rest
+			continue
+		}
+		fields := strings.SplitN(line, " ", 4)
+		switch len(fields) {
+		case 3:
+			// A sum file written before Entry gained Blocks; treat the
+			// block count as unknown rather than failing to load it.
+			file[fields[0]] = Entry{Hash: fields[1], Outputs: strings.Split(fields[2], ",")}
+		case 4:
+			blocks, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return "", nil, fmt.Errorf("%v: malformed line %q: %w", path, line, err)
+			}
+			file[fields[0]] = Entry{Hash: fields[1], Blocks: blocks, Outputs: strings.Split(fields[3], ",")}
+		default:
+			return "", nil, fmt.Errorf("%v: malformed line %q", path, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, fmt.Errorf("read %v: %w", path, err)
+	}
+	return configHash, file, nil
+}
+
+// Save writes a sum file with the given config hash and entries, sorted by
+// input path for a stable diff.
+func Save(path string, configHash string, file File) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %v: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	w := bufio.NewWriter(f)
+	if _, err := fmt.Fprintf(w, "config %s\n", configHash); err != nil {
+		return fmt.Errorf("write %v: %w", path, err)
+	}
+
+	inputs := make([]string, 0, len(file))
+	for input := range file {
+		inputs = append(inputs, input)
+	}
+	sort.Strings(inputs)
+
+	for _, input := range inputs {
+		entry := file[input]
+		if _, err := fmt.Fprintf(w, "%s %s %d %s\n", input, entry.Hash, entry.Blocks, strings.Join(entry.Outputs, ",")); err != nil {
+			return fmt.Errorf("write %v: %w", path, err)
+		}
+	}
+	return w.Flush()
+}
+
+// UpToDate reports whether entry's hash matches content's hash and every
+// one of its recorded outputs still exists on disk.
+func (e Entry) UpToDate(hash string) bool {
+	if e.Hash != hash {
+		return false
+	}
+	for _, out := range e.Outputs {
+		if _, err := os.Stat(out); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package sum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashContentStable(t *testing.T) {
+	a := HashContent([]byte("hello"))
+	b := HashContent([]byte("hello"))
+	if a != b {
+		t.Fatalf("hash not stable: %v != %v", a, b)
+	}
+	if c := HashContent([]byte("world")); c == a {
+		t.Fatal("different content hashed the same")
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+
+	file := File{
+		"en/a.txt": {Hash: "h1:aaaa", Blocks: 3, Outputs: []string{"en/a.json"}},
+		"de/b.txt": {Hash: "h1:bbbb", Blocks: 1, Outputs: []string{"de/b.json", "de/b.go"}},
+	}
+	if err := Save(path, "h1:cfg", file); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	gotCfg, gotFile, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if gotCfg != "h1:cfg" {
+		t.Fatalf("got config %v, want h1:cfg", gotCfg)
+	}
+	if len(gotFile) != len(file) {
+		t.Fatalf("got %d entries, want %d", len(gotFile), len(file))
+	}
+	for k, want := range file {
+		got, ok := gotFile[k]
+		if !ok {
+			t.Fatalf("missing entry %v", k)
+		}
+		if got.Hash != want.Hash || got.Blocks != want.Blocks || len(got.Outputs) != len(want.Outputs) {
+			t.Fatalf("entry %v: got %+v, want %+v", k, got, want)
+		}
+	}
+}
+
+func TestLoadLegacyFormatWithoutBlocks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	content := "config h1:cfg\nen/a.txt h1:aaaa en/a.json\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, gotFile, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	entry, ok := gotFile["en/a.txt"]
+	if !ok {
+		t.Fatal("missing entry for en/a.txt")
+	}
+	if entry.Hash != "h1:aaaa" || entry.Blocks != 0 || len(entry.Outputs) != 1 || entry.Outputs[0] != "en/a.json" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, file, err := Load(filepath.Join(t.TempDir(), "absent.sum"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg != "" || file != nil {
+		t.Fatalf("got cfg=%q file=%v, want empty", cfg, file)
+	}
+}
+
+func TestEntryUpToDate(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "a.json")
+	if err := os.WriteFile(out, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := Entry{Hash: "h1:xxx", Outputs: []string{out}}
+	if !e.UpToDate("h1:xxx") {
+		t.Fatal("expected up to date")
+	}
+	if e.UpToDate("h1:yyy") {
+		t.Fatal("expected stale on hash mismatch")
+	}
+
+	e.Outputs = []string{filepath.Join(dir, "missing.json")}
+	if e.UpToDate("h1:xxx") {
+		t.Fatal("expected stale when output missing")
+	}
+}